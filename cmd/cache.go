@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+	"talisman/detector"
+)
+
+//PruneCache implements `talisman cache prune`: it deletes every results cache entry whose blob is
+//no longer reachable from any ref, so the cache doesn't grow unboundedly as history is rewritten
+//or branches are deleted.
+func PruneCache(repoRoot string) error {
+	cache, err := detector.NewResultsCache(repoRoot)
+	if err != nil {
+		return err
+	}
+	reachable, err := reachableBlobSHAs(repoRoot)
+	if err != nil {
+		return err
+	}
+	pruned, err := cache.Prune(reachable)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("talisman: pruned %d stale cache entries\n", pruned)
+	return nil
+}
+
+//reachableBlobSHAs shells out to `git rev-list --objects --all` to enumerate every object
+//reachable from any ref; the blob entries among them are what the results cache is allowed to keep.
+func reachableBlobSHAs(repoRoot string) (map[string]bool, error) {
+	gitCmd := exec.Command("git", "rev-list", "--objects", "--all")
+	gitCmd.Dir = repoRoot
+	output, err := gitCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("talisman: could not list reachable objects: %w", err)
+	}
+
+	shas := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 {
+			shas[fields[0]] = true
+		}
+	}
+	return shas, nil
+}
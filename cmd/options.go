@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"talisman/detector"
+)
+
+//Options holds the command line flags that configure a single talisman run.
+type Options struct {
+	ReportFormat      string
+	ReportOutput      string
+	SeverityThreshold string
+	UpdateRC          string
+	SkipHidden        bool
+	NoCache           bool
+}
+
+//NewOptions parses the talisman command line flags out of args.
+func NewOptions(args []string) (*Options, error) {
+	fs := flag.NewFlagSet("talisman", flag.ContinueOnError)
+	opts := &Options{}
+	fs.StringVar(&opts.ReportFormat, "report-format", "table", "Report output format: table, json or sarif")
+	fs.StringVar(&opts.ReportOutput, "report-output", "", "File to write the report to; defaults to stdout")
+	fs.StringVar(&opts.SeverityThreshold, "severity-threshold", "low", "Only fail the run for detections at or above this severity: low, medium, high or critical")
+	fs.StringVar(&opts.UpdateRC, "update-rc", "", "Apply the suggested .talismanrc entries instead of only printing them: merge, replace or interactive")
+	fs.BoolVar(&opts.SkipHidden, "skip-hidden", false, "Ignore hidden files the same way .gitignore entries are ignored")
+	fs.BoolVar(&opts.NoCache, "no-cache", false, "Disable the persistent results cache and rescan every file")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+//Severity parses SeverityThreshold into a detector.Severity, defaulting to detector.Low.
+func (o *Options) Severity() detector.Severity {
+	return detector.ParseSeverity(o.SeverityThreshold)
+}
+
+//ShouldUpdateRC answers whether --update-rc was passed with a non-empty value.
+func (o *Options) ShouldUpdateRC() bool {
+	return o.UpdateRC != ""
+}
+
+//UpdateMode parses UpdateRC into a detector.UpdateMode, defaulting to detector.UpdateModeMerge.
+func (o *Options) UpdateMode() detector.UpdateMode {
+	return detector.ParseUpdateMode(o.UpdateRC)
+}
+
+//ResultsCache builds the detector.ResultsCache for this run rooted at repoRoot, or nil when
+//--no-cache was passed.
+func (o *Options) ResultsCache(repoRoot string) (*detector.ResultsCache, error) {
+	if o.NoCache {
+		return nil, nil
+	}
+	return detector.NewResultsCache(repoRoot)
+}
+
+//ReportWriter opens the destination named by ReportOutput, or os.Stdout if it is empty, and
+//returns a closer that the caller should defer even when the writer is stdout.
+func (o *Options) ReportWriter() (io.Writer, func() error, error) {
+	if o.ReportOutput == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	file, err := os.Create(o.ReportOutput)
+	if err != nil {
+		return nil, nil, fmt.Errorf("talisman: could not open report output %s: %w", o.ReportOutput, err)
+	}
+	return file, file.Close, nil
+}
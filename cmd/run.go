@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"talisman/detector"
+	"talisman/git_repo"
+)
+
+//Execute is talisman's entrypoint: it special-cases `talisman cache prune`, and otherwise parses
+//args into Options, marks repoRoot's git-tracked files ignored per .gitignore, --skip-hidden and
+//.talismanrc patterns, renders the report and optionally updates .talismanrc, then returns the
+//process exit code. It runs no detector itself, so the results hold only what those passes record.
+func Execute(args []string, repoRoot string) int {
+	if len(args) >= 2 && args[0] == "cache" && args[1] == "prune" {
+		if err := PruneCache(repoRoot); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	opts, err := NewOptions(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	talismanRCPath := filepath.Join(repoRoot, ".talismanrc")
+	rootConfigs, err := detector.LoadTalismanRCConfigs(talismanRCPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	patternIgnores, err := detector.NewPatternIgnores(repoRoot, rootConfigs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	gitIgnores, err := detector.NewGitIgnores(repoRoot)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cache, err := opts.ResultsCache(repoRoot)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	results := detector.NewDetectionResults(cache)
+
+	filePaths := trackedFilePaths(repoRoot)
+	results.ApplyGitIgnores(gitIgnores, filePaths)
+	results.ApplyHiddenFileIgnores(filePaths, opts.SkipHidden)
+	for _, filePath := range filePaths {
+		if patternIgnores.IsIgnored(filePath) {
+			results.Ignore(filePath, "pattern")
+		}
+	}
+
+	writer, closeWriter, err := opts.ReportWriter()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeWriter()
+
+	if supplement := results.Report(opts.ReportFormat, writer); supplement != "" {
+		fmt.Print(supplement)
+	}
+
+	if opts.ShouldUpdateRC() {
+		var approve func(detector.FileIgnoreConfig) bool
+		if opts.UpdateMode() == detector.UpdateModeInteractive {
+			approve = NewInteractiveApprover(os.Stdin).Approve
+		}
+		if err := results.WriteTalismanRC(talismanRCPath, failingFilePaths(results), opts.UpdateMode(), approve); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	if results.HasFailuresAboveSeverity(opts.Severity()) {
+		return 1
+	}
+	return 0
+}
+
+//trackedFilePaths shells out to `git ls-files` to enumerate the files talisman scans: every file
+//tracked in repoRoot's index, not only the ones a commit would touch.
+func trackedFilePaths(repoRoot string) []git_repo.FilePath {
+	gitCmd := exec.Command("git", "ls-files")
+	gitCmd.Dir = repoRoot
+	output, err := gitCmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var filePaths []git_repo.FilePath
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			filePaths = append(filePaths, git_repo.FilePath(line))
+		}
+	}
+	return filePaths
+}
+
+//failingFilePaths returns the paths results.Failures recorded a failure against, in the shape
+//WriteTalismanRC expects for building its suggested .talismanrc entries.
+func failingFilePaths(results *detector.DetectionResults) []string {
+	var filePaths []string
+	for filePath := range results.Failures {
+		filePaths = append(filePaths, string(filePath))
+	}
+	return filePaths
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"talisman/detector"
+)
+
+//InteractiveApprover asks the user once per newly suggested .talismanrc entry, in the style of
+//`git add -p`. It wraps a single bufio.Reader shared across every prompt in a run, so a multi-line
+//answer delivered to one Read syscall (e.g. piped input) isn't discarded between prompts the way a
+//fresh reader per call would discard it.
+type InteractiveApprover struct {
+	reader *bufio.Reader
+}
+
+//NewInteractiveApprover returns an InteractiveApprover that reads answers from in, typically os.Stdin.
+func NewInteractiveApprover(in io.Reader) *InteractiveApprover {
+	return &InteractiveApprover{reader: bufio.NewReader(in)}
+}
+
+//Approve is the approve callback passed to DetectionResults.WriteTalismanRC for --update-rc=interactive.
+func (a *InteractiveApprover) Approve(config detector.FileIgnoreConfig) bool {
+	fmt.Printf("Add %s to .talismanrc? [y,n] ", config.FileName)
+	answer, _ := a.reader.ReadString('\n')
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y")
+}
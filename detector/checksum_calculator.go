@@ -0,0 +1,22 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+)
+
+//CalculateCollectiveHash returns a single sha256 checksum covering the contents of every file in filePaths.
+//It is used to pin a FileIgnoreConfig entry to the exact file contents that were reviewed and accepted,
+//so that a subsequent, unreviewed change to the file is flagged again instead of silently staying ignored.
+func CalculateCollectiveHash(filePaths []string) string {
+	hasher := sha256.New()
+	for _, filePath := range filePaths {
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		hasher.Write(content)
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
@@ -2,17 +2,27 @@ package detector
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"talisman/git_repo"
 
-	"github.com/olekukonko/tablewriter"
 	yaml "gopkg.in/yaml.v2"
 )
 
 type FailureData struct {
-	Message []string
-	Commits []string
+	Message []string `json:"message"`
+	Commits []string `json:"commits"`
+	//Detector is the id of the Detector that raised this failure (e.g. "filecontent", "filesize").
+	Detector string `json:"detector"`
+	//RuleID identifies the specific rule within Detector that fired, for detectors that check more
+	//than one thing (e.g. a particular regex name within the content detector).
+	RuleID string `json:"ruleId"`
+	//Severity carries how serious this failure is, so reporting and ignore-tuning can gate on it
+	//independently of the message text a detector happens to produce.
+	Severity Severity `json:"severity"`
 }
 
 //DetectionResults represents all interesting information collected during a detection run.
@@ -22,20 +32,56 @@ type FailureData struct {
 type DetectionResults struct {
 	Failures map[git_repo.FilePath][]FailureData
 	ignores  map[git_repo.FilePath][]string
+	//cache is consulted by ShouldSkip/RecordCache so unchanged blobs can skip a rescan. It is nil
+	//when the run was started with --no-cache, in which case both are no-ops.
+	cache *ResultsCache
 }
 
-//NewDetectionResults is a new DetectionResults struct. It represents the pre-run state of a Detection run.
-func NewDetectionResults() *DetectionResults {
-	result := DetectionResults{make(map[git_repo.FilePath][]FailureData), make(map[git_repo.FilePath][]string)}
+//NewDetectionResults is a new DetectionResults struct. It represents the pre-run state of a
+//Detection run. cache may be nil (e.g. when the run was started with --no-cache), in which case
+//ShouldSkip/RecordCache become no-ops.
+func NewDetectionResults(cache *ResultsCache) *DetectionResults {
+	result := DetectionResults{make(map[git_repo.FilePath][]FailureData), make(map[git_repo.FilePath][]string), cache}
 	return &result
 }
 
+//ShouldSkip answers whether a cached outcome already exists for blobSHA, scanned by detectorName
+//under configHash; if so, that outcome's failures are replayed into this run via Fail and the
+//detector can skip rescanning the blob. severity is used for any replayed failures, since the
+//cache only stores the message text.
+func (r *DetectionResults) ShouldSkip(filePath git_repo.FilePath, blobSHA string, detectorName string, configHash string, severity Severity) bool {
+	if r.cache == nil {
+		return false
+	}
+	outcome, ok := r.cache.Lookup(blobSHA, detectorName, configHash)
+	if !ok {
+		return false
+	}
+	for _, message := range outcome.Failures {
+		r.Fail(filePath, detectorName, "", severity, message, nil)
+	}
+	return true
+}
+
+//RecordCache persists the outcome of running detectorName, configured under configHash, against
+//blobSHA, so a future ShouldSkip against the same blob/detector/config can skip the rescan.
+//failureMessages is empty for a clean scan.
+func (r *DetectionResults) RecordCache(blobSHA string, detectorName string, configHash string, failureMessages []string) error {
+	if r.cache == nil {
+		return nil
+	}
+	return r.cache.Store(blobSHA, detectorName, configHash, failureMessages)
+}
+
 //Fail is used to mark the supplied FilePath as failing a detection for a supplied reason.
+//detector and ruleID identify which check fired (e.g. "filecontent", "aws-secret-key"), and
+//severity how serious that check considers the failure; together they let reporting and
+//ignore-tuning work at finer grain than the message text alone.
 //Detectors are encouraged to provide context sensitive messages so that fixing the errors is made simple for the end user
 //Fail may be called multiple times for each FilePath and the calls accumulate the provided reasons
-func (r *DetectionResults) Fail(filePath git_repo.FilePath, message string, commits []string) {
+func (r *DetectionResults) Fail(filePath git_repo.FilePath, detector string, ruleID string, severity Severity, message string, commits []string) {
 	errors, ok := r.Failures[filePath]
-	failureData := NewFaulureData([]string{message}, commits)
+	failureData := NewFaulureData(detector, ruleID, severity, []string{message}, commits)
 	if !ok {
 		r.Failures[filePath] = []FailureData{failureData}
 	} else {
@@ -69,54 +115,124 @@ func (r *DetectionResults) Successful() bool {
 	return !r.HasFailures()
 }
 
+//HasFailuresAboveSeverity answers if any failure detected in the current run is at least as
+//severe as threshold, so callers can gate pre-commit/CI on a --severity-threshold instead of
+//failing on any detection at all.
+func (r *DetectionResults) HasFailuresAboveSeverity(threshold Severity) bool {
+	for _, failures := range r.Failures {
+		for _, failureData := range failures {
+			if failureData.Severity.AtLeast(threshold) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 //GetFailures returns the various reasons that a given FilePath was marked as failing by all the detectors in the current run
 func (r *DetectionResults) GetFailures(fileName git_repo.FilePath) []FailureData {
 	return r.Failures[fileName]
 }
 
-//Report returns a string documenting the various Failures and ignored files for the current run
-func (r *DetectionResults) Report() string {
-	var result string
-	var filePathsForIgnoresAndFailures []string
-	var data [][]string
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"File", "Errors"})
-	table.SetRowLine(true)
-
-	for filePath := range r.Failures {
-		filePathsForIgnoresAndFailures = append(filePathsForIgnoresAndFailures, string(filePath))
-		toBeScanned := false
-		failureData := r.ReportFileFailures(filePath, toBeScanned)
-		data = append(data, failureData...)
-	}
-	for filePath := range r.ignores {
-		filePathsForIgnoresAndFailures = append(filePathsForIgnoresAndFailures, string(filePath))
-		// ignoreData := r.ReportFileIgnores(filePath)
-		// data = append(data, ignoreData...)
-	}
-	filePathsForIgnoresAndFailures = unique(filePathsForIgnoresAndFailures)
-	if len(r.Failures) > 0 {
-		fmt.Printf("\n\x1b[1m\x1b[31mTalisman Report:\x1b[0m\x1b[0m\n")
-		table.AppendBulk(data)
-		table.Render()
-		result = result + fmt.Sprintf("\n\x1b[33mIf you are absolutely sure that you want to ignore the above files from talisman detectors, consider pasting the following format in .talismanrc file in the project root\x1b[0m\n")
-		result = result + r.suggestTalismanRC(filePathsForIgnoresAndFailures)
-		result = result + fmt.Sprintf("\n\n")
+//Report renders this run's Failures and ignored files using the Reporter for reportFormat
+//("table", "json" or "sarif"; an unrecognised or empty format falls back to "table"), writing to
+//w, and returns any supplementary text - such as the table reporter's suggested .talismanrc
+//snippet - that the caller should also display.
+func (r *DetectionResults) Report(reportFormat string, w io.Writer) string {
+	reporter := NewReporter(reportFormat)
+	result, err := reporter.Render(r, w)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "talisman: failed to render %s report: %v\n", reportFormat, err)
 	}
 	return result
 }
 
+//suggestTalismanRC builds the FileIgnoreConfig entries to paste into .talismanrc for filePaths.
+//Where three or more sibling files in the same directory, sharing an extension, all failed the
+//same set of detectors, a single gitignore-style pattern (e.g. secrets/*.pem) is emitted in place
+//of one line per file; a pattern entry has no Checksum since it is expected to keep matching files
+//whose contents legitimately vary. Files that don't fall into such a group still get an individual
+//entry pinned with CalculateCollectiveHash, as before.
 func (r *DetectionResults) suggestTalismanRC(filePaths []string) string {
+	talismanRcIgnoreConfig := TalismanRCIgnore{r.suggestedFileIgnoreConfigs(filePaths)}
+	m, _ := yaml.Marshal(&talismanRcIgnoreConfig)
+	return string(m)
+}
+
+//suggestedFileIgnoreConfigs builds the FileIgnoreConfig entries that would ignore filePaths, in
+//the same shape suggestTalismanRC prints for copy-pasting and WriteTalismanRC writes directly.
+func (r *DetectionResults) suggestedFileIgnoreConfigs(filePaths []string) []FileIgnoreConfig {
 	var fileIgnoreConfigs []FileIgnoreConfig
+	for group, paths := range r.groupSiblingsByExtensionAndDetectors(filePaths) {
+		if group.pattern != "" {
+			fileIgnoreConfigs = append(fileIgnoreConfigs, FileIgnoreConfig{FileName: group.pattern, IgnoreDetectors: r.detectorsThatFailed(paths)})
+			continue
+		}
+		for _, filePath := range paths {
+			currentChecksum := CalculateCollectiveHash([]string{filePath})
+			fileIgnoreConfigs = append(fileIgnoreConfigs, FileIgnoreConfig{FileName: filePath, Checksum: currentChecksum, IgnoreDetectors: r.detectorsThatFailed([]string{filePath})})
+		}
+	}
+	return fileIgnoreConfigs
+}
+
+//detectorsThatFailed returns the distinct Detector ids that raised a failure against any of
+//filePaths, so a suggested FileIgnoreConfig scopes IgnoreDetectors to what actually fired instead
+//of leaving it empty (which would silence every detector on that file, not just the ones at fault).
+func (r *DetectionResults) detectorsThatFailed(filePaths []string) []string {
+	seen := make(map[string]bool)
+	var detectors []string
 	for _, filePath := range filePaths {
-		currentChecksum := CalculateCollectiveHash([]string{filePath})
-		fileIgnoreConfig := FileIgnoreConfig{filePath, currentChecksum, []string{}}
-		fileIgnoreConfigs = append(fileIgnoreConfigs, fileIgnoreConfig)
+		for _, failureData := range r.Failures[git_repo.FilePath(filePath)] {
+			if failureData.Detector == "" || seen[failureData.Detector] {
+				continue
+			}
+			seen[failureData.Detector] = true
+			detectors = append(detectors, failureData.Detector)
+		}
 	}
+	return detectors
+}
 
-	talismanRcIgnoreConfig := TalismanRCIgnore{fileIgnoreConfigs}
-	m, _ := yaml.Marshal(&talismanRcIgnoreConfig)
-	return string(m)
+//minSiblingsForPattern is the number of same-directory, same-extension, same-failing-detectors
+//files below which individual entries are still clearer than a collapsed pattern.
+const minSiblingsForPattern = 3
+
+type siblingGroup struct {
+	dir         string
+	ext         string
+	detectorKey string
+	pattern     string
+}
+
+//groupSiblingsByExtensionAndDetectors buckets filePaths by directory, extension, and the exact set
+//of detectors that failed against them, then assigns a "dir/*.ext" pattern to any bucket with at
+//least minSiblingsForPattern members. Requiring the same failing-detector set keeps a collapsed
+//pattern's IgnoreDetectors accurate: siblings that merely share a directory and extension but
+//failed different detectors are never folded together, which would otherwise silence a detector on
+//files it never actually fired against.
+func (r *DetectionResults) groupSiblingsByExtensionAndDetectors(filePaths []string) map[siblingGroup][]string {
+	buckets := make(map[siblingGroup][]string)
+	for _, filePath := range filePaths {
+		dir, file := filepath.Split(filePath)
+		detectors := r.detectorsThatFailed([]string{filePath})
+		sortedDetectors := append([]string(nil), detectors...)
+		sort.Strings(sortedDetectors)
+		key := siblingGroup{dir: dir, ext: filepath.Ext(file), detectorKey: strings.Join(sortedDetectors, ",")}
+		buckets[key] = append(buckets[key], filePath)
+	}
+	result := make(map[siblingGroup][]string, len(buckets))
+	for key, paths := range buckets {
+		if key.ext != "" && len(paths) >= minSiblingsForPattern {
+			key.pattern = key.dir + "*" + key.ext
+			if key.dir == "" {
+				//anchor to the repo root: a bare "*.ext" would match at every depth
+				key.pattern = "/" + key.pattern
+			}
+		}
+		result[key] = paths
+	}
+	return result
 }
 
 //ReportFileFailures adds a string to table documenting the various Failures detected on the supplied FilePath by all detectors in the current run
@@ -130,9 +246,9 @@ func (r *DetectionResults) ReportFileFailures(filePath git_repo.FilePath, toBeSc
 					failureMessage = failureMessage[:150] + "\n" + failureMessage[150:]
 				}
 				if toBeScanned {
-					data = append(data, []string{string(filePath), failureMessage, strings.Join(failureData.Commits, "\n")})
+					data = append(data, []string{string(filePath), failureMessage, string(failureData.Severity), strings.Join(failureData.Commits, "\n")})
 				} else {
-					data = append(data, []string{string(filePath), failureMessage})
+					data = append(data, []string{string(filePath), failureMessage, string(failureData.Severity)})
 				}
 			}
 		}
@@ -164,9 +280,12 @@ func unique(stringSlice []string) []string {
 	return list
 }
 
-func NewFaulureData(message []string, commits []string) FailureData {
+func NewFaulureData(detector string, ruleID string, severity Severity, message []string, commits []string) FailureData {
 	return FailureData{
-		Message: message,
-		Commits: commits,
+		Message:  message,
+		Commits:  commits,
+		Detector: detector,
+		RuleID:   ruleID,
+		Severity: severity,
 	}
 }
@@ -0,0 +1,26 @@
+package detector
+
+import "strings"
+
+//FileIgnoreConfig represents a single entry in the .talismanrc file.
+//FileName may either name a single concrete file or, since the FileName field now accepts
+//.gitignore-style syntax, a pattern (globs, `**`, `!` negation, trailing `/` for directory-only)
+//that matches many files at once.
+//Checksum pins the entry to the reviewed contents of the file it names. It only makes sense for a
+//concrete FileName and is therefore optional: pattern entries are expected to match files whose
+//contents legitimately vary, so leaving Checksum empty is normal for them.
+type FileIgnoreConfig struct {
+	FileName        string   `yaml:"filename"`
+	Checksum        string   `yaml:"checksum,omitempty"`
+	IgnoreDetectors []string `yaml:"ignore_detectors"`
+}
+
+//IsPattern answers whether FileName uses gitignore-style glob syntax rather than naming a single concrete file.
+func (f FileIgnoreConfig) IsPattern() bool {
+	return strings.ContainsAny(f.FileName, "*?[") || strings.HasPrefix(f.FileName, "!") || strings.HasSuffix(f.FileName, "/")
+}
+
+//TalismanRCIgnore represents the parsed contents of a .talismanrc file.
+type TalismanRCIgnore struct {
+	FileIgnoreConfig []FileIgnoreConfig `yaml:"fileignoreconfig"`
+}
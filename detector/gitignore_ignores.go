@@ -0,0 +1,83 @@
+package detector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"talisman/git_repo"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+//GitIgnores matches paths against the patterns a plain `git` would already treat as ignored:
+//every .gitignore under the repo, .git/info/exclude, and the user's core.excludesfile. Consulting
+//it before detectors run keeps vendored/build directories, which users already expect git (and now
+//talisman) to leave alone, out of the scan.
+type GitIgnores struct {
+	matcher *gitignore.GitIgnore
+}
+
+//NewGitIgnores compiles every .gitignore under repoRoot, in ascending priority order (repo root
+//first, nested directories layered on top so their patterns win, matching go-git's
+//gitignore.ReadPatterns), followed by .git/info/exclude and the user's core.excludesfile.
+func NewGitIgnores(repoRoot string) (*GitIgnores, error) {
+	lines, err := readNestedPatternFiles(repoRoot, ".gitignore", func(path string) bool {
+		return strings.Contains(path, string(os.PathSeparator)+".git"+string(os.PathSeparator))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	excludeLines, err := readPatternFile(filepath.Join(repoRoot, ".git", "info", "exclude"))
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, excludeLines...)
+
+	globalLines, err := readPatternFile(coreExcludesFilePath(repoRoot))
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, globalLines...)
+
+	return &GitIgnores{matcher: gitignore.CompileIgnoreLines(lines...)}, nil
+}
+
+//IsIgnored answers whether path matches a compiled .gitignore-style pattern.
+func (g *GitIgnores) IsIgnored(path git_repo.FilePath) bool {
+	if g == nil || g.matcher == nil {
+		return false
+	}
+	return g.matcher.MatchesPath(string(path))
+}
+
+var excludesFileLine = regexp.MustCompile(`(?m)^\s*excludesfile\s*=\s*(.+?)\s*$`)
+
+//coreExcludesFilePath resolves git's core.excludesfile: the path configured in repoRoot's
+//.git/config, falling back to git's own default of $HOME/.config/git/ignore when unset.
+func coreExcludesFilePath(repoRoot string) string {
+	config, err := ioutil.ReadFile(filepath.Join(repoRoot, ".git", "config"))
+	if err == nil {
+		if match := excludesFileLine.FindSubmatch(config); match != nil {
+			return expandHome(string(match[1]))
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "git", "ignore")
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package detector
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+//IsHidden answers whether path names a dotfile, the Unix convention for "hidden".
+func IsHidden(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), ".")
+}
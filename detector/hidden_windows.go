@@ -0,0 +1,26 @@
+//go:build windows
+
+package detector
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+//IsHidden answers whether path is hidden: a dotfile (the Unix convention, kept so behaviour stays
+//consistent for repos shared across platforms) or carries the Windows FILE_ATTRIBUTE_HIDDEN bit.
+func IsHidden(path string) bool {
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		return true
+	}
+	pointer, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+	attributes, err := syscall.GetFileAttributes(pointer)
+	if err != nil {
+		return false
+	}
+	return attributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
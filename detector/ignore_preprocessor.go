@@ -0,0 +1,31 @@
+package detector
+
+import "talisman/git_repo"
+
+//ApplyGitIgnores marks each of filePaths that gitIgnores matches as Ignore'd with the "gitignore"
+//reason, before any detector runs against it. This is a preprocessing pass: it keeps files git
+//already ignores out of scope for talisman too, layering .talismanrc's own ignores on top rather
+//than requiring them to be duplicated there.
+func (r *DetectionResults) ApplyGitIgnores(gitIgnores *GitIgnores, filePaths []git_repo.FilePath) {
+	if gitIgnores == nil {
+		return
+	}
+	for _, filePath := range filePaths {
+		if gitIgnores.IsIgnored(filePath) {
+			r.Ignore(filePath, "gitignore")
+		}
+	}
+}
+
+//ApplyHiddenFileIgnores marks each of filePaths that IsHidden as Ignore'd with the "hidden" reason,
+//when skipHidden is enabled via --skip-hidden.
+func (r *DetectionResults) ApplyHiddenFileIgnores(filePaths []git_repo.FilePath, skipHidden bool) {
+	if !skipHidden {
+		return
+	}
+	for _, filePath := range filePaths {
+		if IsHidden(string(filePath)) {
+			r.Ignore(filePath, "hidden")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package detector
+
+import (
+	"encoding/json"
+	"io"
+	"talisman/git_repo"
+)
+
+//JSONReporter renders a DetectionResults run as a single JSON document, for CI systems and
+//dashboards that want to consume talisman's results programmatically rather than scrape the table.
+type JSONReporter struct{}
+
+type jsonReport struct {
+	Successful bool                     `json:"successful"`
+	Failures   map[string][]FailureData `json:"failures"`
+	Ignores    map[string][]string      `json:"ignores"`
+}
+
+//Render encodes the run as indented JSON to w. JSON carries no separate "suggested .talismanrc"
+//text, so the returned string is always empty.
+func (j *JSONReporter) Render(r *DetectionResults, w io.Writer) (string, error) {
+	report := jsonReport{
+		Successful: r.Successful(),
+		Failures:   stringKeyedFailures(r.Failures),
+		Ignores:    stringKeyedIgnores(r.ignores),
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+func stringKeyedFailures(failures map[git_repo.FilePath][]FailureData) map[string][]FailureData {
+	result := make(map[string][]FailureData, len(failures))
+	for path, data := range failures {
+		result[string(path)] = data
+	}
+	return result
+}
+
+func stringKeyedIgnores(ignores map[git_repo.FilePath][]string) map[string][]string {
+	result := make(map[string][]string, len(ignores))
+	for path, detectors := range ignores {
+		result[string(path)] = detectors
+	}
+	return result
+}
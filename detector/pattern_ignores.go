@@ -0,0 +1,130 @@
+package detector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"talisman/git_repo"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+//PatternIgnores compiles the gitignore-style patterns collected from .talismanrc (repo root and
+//nested) and .talisman/info/exclude into a single matcher that is consulted for every
+//git_repo.FilePath before detectors run. Patterns are layered in ascending priority order,
+//repo-root first, so that a nested .talismanrc closer to the file being evaluated always wins,
+//matching the semantics of go-git's gitignore.ReadPatterns.
+type PatternIgnores struct {
+	matcher *gitignore.GitIgnore
+}
+
+//NewPatternIgnores builds a PatternIgnores for repoRoot from the FileIgnoreConfig entries of the
+//top-level .talismanrc (rootConfigs), layers on repoRoot's .talisman/info/exclude - a supplementary,
+//unversioned exclude file analogous to .git/info/exclude, for patterns a contributor wants ignored
+//locally without committing them to .talismanrc - then layers on patterns read recursively from any
+//nested .talismanrc files below repoRoot, deeper files winning. .git/ is implicitly ignored unless
+//one of the supplied patterns negates it with "!".
+func NewPatternIgnores(repoRoot string, rootConfigs []FileIgnoreConfig) (*PatternIgnores, error) {
+	lines := []string{".git/"}
+
+	for _, config := range rootConfigs {
+		if config.FileName == "" || strings.HasPrefix(config.FileName, "#") {
+			continue
+		}
+		lines = append(lines, config.FileName)
+	}
+
+	excludeLines, err := readPatternFile(filepath.Join(repoRoot, ".talisman", "info", "exclude"))
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, excludeLines...)
+
+	nested, err := readNestedTalismanRCPatterns(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, nested...)
+
+	return &PatternIgnores{matcher: gitignore.CompileIgnoreLines(lines...)}, nil
+}
+
+//IsIgnored answers whether path matches one of the compiled patterns.
+func (p *PatternIgnores) IsIgnored(path git_repo.FilePath) bool {
+	if p == nil || p.matcher == nil {
+		return false
+	}
+	return p.matcher.MatchesPath(string(path))
+}
+
+//readNestedTalismanRCPatterns walks repoRoot for every .talismanrc below the root, rewriting each
+//pattern relative to repoRoot so it only applies under the directory that declared it.
+func readNestedTalismanRCPatterns(repoRoot string) ([]string, error) {
+	rootRC := filepath.Join(repoRoot, ".talismanrc")
+	return readNestedPatternFiles(repoRoot, ".talismanrc", func(path string) bool {
+		return path == rootRC
+	})
+}
+
+//readNestedPatternFiles walks repoRoot for every file named fileName, skipping any for which skip
+//returns true, and rewrites each pattern it contains relative to repoRoot by prefixing it with the
+//directory that declared it (see scopePattern). A pattern with no slash therefore no longer matches
+//at every depth below that directory: "*.log" in sub/.gitignore becomes "sub/*.log", which matches
+//files directly in sub but not in its subdirectories.
+func readNestedPatternFiles(repoRoot string, fileName string, skip func(path string) bool) ([]string, error) {
+	var lines []string
+	err := filepath.Walk(repoRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Name() != fileName || skip(path) {
+			return err
+		}
+		patterns, readErr := readPatternFile(path)
+		if readErr != nil {
+			return readErr
+		}
+		rel, relErr := filepath.Rel(repoRoot, filepath.Dir(path))
+		if relErr != nil {
+			return relErr
+		}
+		for _, pattern := range patterns {
+			if rel != "." {
+				pattern = scopePattern(pattern, rel)
+			}
+			lines = append(lines, pattern)
+		}
+		return nil
+	})
+	return lines, err
+}
+
+//scopePattern rewrites pattern, read from a nested pattern file, so it only applies under rel -
+//the directory, relative to repoRoot, that declared it. A negated pattern ("!foo") keeps its "!"
+//in front of the rewritten path rather than having rel folded into the negation marker itself,
+//so "!keep.log" in "sub/.gitignore" becomes "!sub/keep.log", not "sub/!keep.log".
+func scopePattern(pattern string, rel string) string {
+	if strings.HasPrefix(pattern, "!") {
+		return "!" + filepath.Join(rel, strings.TrimPrefix(pattern, "!"))
+	}
+	return filepath.Join(rel, pattern)
+}
+
+//readPatternFile reads a gitignore-style pattern file, skipping blank lines and comments.
+//A missing file is not an error: it simply contributes no patterns.
+func readPatternFile(path string) ([]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
@@ -0,0 +1,48 @@
+package detector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScopePatternKeepsNegationMarkerInFront(t *testing.T) {
+	got := scopePattern("!keep.log", "sub")
+	want := "!sub/keep.log"
+	if got != want {
+		t.Fatalf("scopePattern(%q, %q) = %q, want %q", "!keep.log", "sub", got, want)
+	}
+}
+
+func TestNestedNegatedPatternOnlyUnignoresUnderItsOwnDirectory(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "talisman-pattern-ignores")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoRoot)
+
+	subDir := filepath.Join(repoRoot, "sub")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(subDir, ".talismanrc"), []byte("!keep.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootConfigs := []FileIgnoreConfig{{FileName: "*.log"}}
+	patternIgnores, err := NewPatternIgnores(repoRoot, rootConfigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !patternIgnores.IsIgnored("keep.log") {
+		t.Errorf("expected repo-root keep.log to stay ignored (*.log); the nested negation should not have reached it")
+	}
+	if patternIgnores.IsIgnored("sub/keep.log") {
+		t.Errorf("expected sub/keep.log to be un-ignored by sub/.talismanrc's !keep.log")
+	}
+	if !patternIgnores.IsIgnored("other/keep.log") {
+		t.Errorf("expected other/keep.log to stay ignored (*.log); the nested negation should not have reached it")
+	}
+}
@@ -0,0 +1,23 @@
+package detector
+
+import "io"
+
+//Reporter renders a completed DetectionResults run in a specific output format.
+//Render writes the report to w and returns any supplementary text, such as a suggested
+//.talismanrc snippet, that the caller should also surface on the console.
+type Reporter interface {
+	Render(r *DetectionResults, w io.Writer) (string, error)
+}
+
+//NewReporter resolves the Reporter for the named report format. An unrecognised or empty format
+//falls back to the table reporter talisman has always used.
+func NewReporter(format string) Reporter {
+	switch format {
+	case "json":
+		return &JSONReporter{}
+	case "sarif":
+		return &SARIFReporter{}
+	default:
+		return &TableReporter{}
+	}
+}
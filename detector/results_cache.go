@@ -0,0 +1,109 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//cacheFormatVersion is bumped whenever the shape of a cached entry changes, so a cache written by
+//an older talisman is ignored rather than misread.
+const cacheFormatVersion = 1
+
+//CacheOutcome is the cached result of running one detector, under one detector-config, against
+//one git blob.
+type CacheOutcome struct {
+	Version    int      `json:"version"`
+	Detector   string   `json:"detector"`
+	ConfigHash string   `json:"configHash"`
+	Failures   []string `json:"failures,omitempty"`
+}
+
+//ResultsCache persists per-blob detection outcomes under .git/talisman-cache/ so that a re-scan of
+//a blob whose SHA, detector id and detector-config hash are all unchanged since the last run can
+//be skipped entirely. This turns pre-commit hook latency from O(all tracked files) into
+//O(changed files) on large repos.
+type ResultsCache struct {
+	dir string
+}
+
+//NewResultsCache returns a ResultsCache rooted at repoRoot's .git/talisman-cache/ directory,
+//creating it if it doesn't already exist.
+func NewResultsCache(repoRoot string) (*ResultsCache, error) {
+	dir := filepath.Join(repoRoot, ".git", "talisman-cache")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &ResultsCache{dir: dir}, nil
+}
+
+//Lookup returns the cached outcome for blobSHA/detectorName/configHash and whether one was found.
+//ok is false both when nothing was ever cached and when the cached entry's Version or ConfigHash
+//no longer matches, so a stale or reconfigured detector is rescanned rather than trusted.
+func (c *ResultsCache) Lookup(blobSHA string, detectorName string, configHash string) (CacheOutcome, bool) {
+	if c == nil {
+		return CacheOutcome{}, false
+	}
+	content, err := ioutil.ReadFile(c.entryPath(blobSHA, detectorName))
+	if err != nil {
+		return CacheOutcome{}, false
+	}
+	var outcome CacheOutcome
+	if err := json.Unmarshal(content, &outcome); err != nil {
+		return CacheOutcome{}, false
+	}
+	if outcome.Version != cacheFormatVersion || outcome.ConfigHash != configHash {
+		return CacheOutcome{}, false
+	}
+	return outcome, true
+}
+
+//Store records the outcome of running detectorName, configured per configHash, against blobSHA.
+//failures is empty for a clean scan.
+func (c *ResultsCache) Store(blobSHA string, detectorName string, configHash string, failures []string) error {
+	if c == nil {
+		return nil
+	}
+	outcome := CacheOutcome{Version: cacheFormatVersion, Detector: detectorName, ConfigHash: configHash, Failures: failures}
+	content, err := json.Marshal(outcome)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.entryPath(blobSHA, detectorName), content, 0644)
+}
+
+//Prune deletes every cache entry whose blob SHA is absent from reachableBlobSHAs - the set of
+//blobs still reachable from some ref, which the caller is expected to have computed (e.g. via
+//`git rev-list --objects --all`) - and returns how many entries it removed.
+func (c *ResultsCache) Prune(reachableBlobSHAs map[string]bool) (int, error) {
+	entries, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return 0, err
+	}
+	pruned := 0
+	for _, entry := range entries {
+		blobSHA := strings.SplitN(entry.Name(), "-", 2)[0]
+		if reachableBlobSHAs[blobSHA] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+func (c *ResultsCache) entryPath(blobSHA string, detectorName string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s.json", blobSHA, detectorName))
+}
+
+//ConfigHash returns a stable hash for a detector's configuration (e.g. its ignore patterns), so
+//Lookup/Store can tell two runs of the same detector under different settings apart.
+func ConfigHash(detectorConfig string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(detectorConfig)))
+}
@@ -0,0 +1,155 @@
+package detector
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+const sarifVersion = "2.1.0"
+
+//SARIFReporter renders a DetectionResults run as a SARIF 2.1.0 log, the format GitHub code
+//scanning and most CI dashboards expect from a static analysis tool.
+type SARIFReporter struct{}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+//Render encodes the run as a SARIF 2.1.0 log to w. Each failure message becomes one result, with
+//its ruleId derived from the detector that raised it (falling back to the message text for
+//failures raised before detectors tagged themselves), its level derived from FailureData.Severity,
+//and a partialFingerprint seeded from the collective hash so GitHub code scanning can de-duplicate
+//the same finding across runs.
+func (s *SARIFReporter) Render(r *DetectionResults, w io.Writer) (string, error) {
+	var results []sarifResult
+	ruleIDs := make(map[string]bool)
+
+	for filePath, failures := range r.Failures {
+		for _, failureData := range failures {
+			for _, message := range failureData.Message {
+				ruleID := sarifRuleID(failureData)
+				ruleIDs[ruleID] = true
+				results = append(results, sarifResult{
+					RuleID:  ruleID,
+					Level:   sarifLevel(failureData.Severity),
+					Message: sarifMessage{Text: message},
+					Locations: []sarifLocation{{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: string(filePath)},
+						},
+					}},
+					PartialFingerprints: map[string]string{
+						"talismanCollectiveHash/v1": CalculateCollectiveHash([]string{string(filePath)}),
+					},
+				})
+			}
+		}
+	}
+
+	var rules []sarifRule
+	for ruleID := range ruleIDs {
+		rules = append(rules, sarifRule{ID: ruleID})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "talisman",
+				InformationURI: "https://github.com/thoughtworks/talisman",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(log); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+//sarifRuleID derives the SARIF ruleId for a failure: Detector/RuleID when the detector tagged
+//itself, otherwise a best-effort id taken from the text before the first colon of the first failure
+//message, or from its leading word when it has no colon.
+func sarifRuleID(failureData FailureData) string {
+	if failureData.Detector != "" {
+		if failureData.RuleID != "" {
+			return failureData.Detector + "/" + failureData.RuleID
+		}
+		return failureData.Detector
+	}
+	if len(failureData.Message) == 0 {
+		return "talisman/detection"
+	}
+	message := failureData.Message[0]
+	cut := strings.IndexAny(message, ":")
+	if cut == -1 {
+		cut = strings.IndexByte(message, ' ')
+	}
+	if cut == -1 {
+		return "talisman/detection"
+	}
+	return "talisman/" + strings.ToLower(strings.TrimSpace(message[:cut]))
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case Critical, High:
+		return "error"
+	case Low:
+		return "note"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,52 @@
+package detector
+
+import "strings"
+
+//Severity captures how serious a single failure is, so reporting and ignore-tuning can gate on it
+//independently of the message text a detector happens to produce.
+type Severity string
+
+const (
+	Low      Severity = "low"
+	Medium   Severity = "medium"
+	High     Severity = "high"
+	Critical Severity = "critical"
+)
+
+//severityRank orders Severity from least to most serious so thresholds can be compared.
+var severityRank = map[Severity]int{
+	Low:      0,
+	Medium:   1,
+	High:     2,
+	Critical: 3,
+}
+
+//rank returns this severity's position in severityRank, treating an empty or unrecognised
+//Severity as Medium so failures raised before detectors tagged a severity still gate sensibly.
+func (s Severity) rank() int {
+	if rank, ok := severityRank[s]; ok {
+		return rank
+	}
+	return severityRank[Medium]
+}
+
+//AtLeast answers whether s is at least as serious as threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+//ParseSeverity parses a --severity-threshold flag value, defaulting to Low (the least strict
+//threshold, so an unrecognised value doesn't accidentally suppress every failure) when name is
+//empty or unknown.
+func ParseSeverity(name string) Severity {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "medium":
+		return Medium
+	case "high":
+		return High
+	case "critical":
+		return Critical
+	default:
+		return Low
+	}
+}
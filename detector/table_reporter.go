@@ -0,0 +1,43 @@
+package detector
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+//TableReporter renders a DetectionResults run as the colourised console table talisman has always
+//printed, and builds a suggested .talismanrc snippet for any failing files.
+type TableReporter struct{}
+
+//Render writes the table to w and returns the suggested .talismanrc snippet without writing it to
+//w, so callers that built their own output around Report's historical return value keep working.
+func (t *TableReporter) Render(r *DetectionResults, w io.Writer) (string, error) {
+	var result string
+	var filePathsForIgnoresAndFailures []string
+	var data [][]string
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"File", "Errors", "Severity"})
+	table.SetRowLine(true)
+
+	for filePath := range r.Failures {
+		filePathsForIgnoresAndFailures = append(filePathsForIgnoresAndFailures, string(filePath))
+		toBeScanned := false
+		failureData := r.ReportFileFailures(filePath, toBeScanned)
+		data = append(data, failureData...)
+	}
+	for filePath := range r.ignores {
+		filePathsForIgnoresAndFailures = append(filePathsForIgnoresAndFailures, string(filePath))
+	}
+	filePathsForIgnoresAndFailures = unique(filePathsForIgnoresAndFailures)
+	if len(r.Failures) > 0 {
+		fmt.Fprintf(w, "\n\x1b[1m\x1b[31mTalisman Report:\x1b[0m\x1b[0m\n")
+		table.AppendBulk(data)
+		table.Render()
+		result = result + fmt.Sprintf("\n\x1b[33mIf you are absolutely sure that you want to ignore the above files from talisman detectors, consider pasting the following format in .talismanrc file in the project root\x1b[0m\n")
+		result = result + r.suggestTalismanRC(filePathsForIgnoresAndFailures)
+		result = result + fmt.Sprintf("\n\n")
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package detector
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+//WriteTalismanRC reconciles the FileIgnoreConfig entries this run suggests for filePaths with
+//whatever already lives at path (typically .talismanrc), according to mode, and writes the result
+//back to path. This turns the historical "paste this snippet" workflow into a one-command
+//operation. In UpdateModeInteractive, approve is called once per newly suggested entry and the
+//entry is only kept when approve returns true; approve is ignored for the other modes.
+func (r *DetectionResults) WriteTalismanRC(path string, filePaths []string, mode UpdateMode, approve func(FileIgnoreConfig) bool) error {
+	suggested := r.suggestedFileIgnoreConfigs(filePaths)
+	existing, leadingComments, err := readTalismanRC(path)
+	if err != nil {
+		return err
+	}
+
+	if mode == UpdateModeReplace {
+		return writeTalismanRC(path, leadingComments, suggested)
+	}
+
+	if mode == UpdateModeInteractive {
+		suggested = approveNewEntries(existing, suggested, approve)
+	}
+
+	merged := mergeFileIgnoreConfigs(existing, suggested)
+	return writeTalismanRC(path, leadingComments, merged)
+}
+
+//approveNewEntries filters suggested down to the entries approve accepts, but only asks about
+//entries that aren't already present unchanged in existing - there is nothing to confirm about an
+//entry WriteTalismanRC would otherwise leave alone.
+func approveNewEntries(existing TalismanRCIgnore, suggested []FileIgnoreConfig, approve func(FileIgnoreConfig) bool) []FileIgnoreConfig {
+	byName := make(map[string]FileIgnoreConfig, len(existing.FileIgnoreConfig))
+	for _, config := range existing.FileIgnoreConfig {
+		byName[config.FileName] = config
+	}
+
+	var accepted []FileIgnoreConfig
+	for _, config := range suggested {
+		if current, ok := byName[config.FileName]; ok && current.Checksum == config.Checksum {
+			accepted = append(accepted, config)
+			continue
+		}
+		if approve == nil || approve(config) {
+			accepted = append(accepted, config)
+		}
+	}
+	return accepted
+}
+
+//mergeFileIgnoreConfigs combines existing's entries with suggested, preserving existing's order
+//and any manually curated IgnoreDetectors, deduplicated by FileName. A suggested entry whose
+//FileName already exists only refreshes the stored Checksum - for a concrete (non-pattern) file
+//whose content has changed since it was last ignored - and otherwise leaves the entry alone.
+//Entries present only in suggested are appended in the order they were suggested.
+func mergeFileIgnoreConfigs(existing TalismanRCIgnore, suggested []FileIgnoreConfig) []FileIgnoreConfig {
+	merged := make([]FileIgnoreConfig, len(existing.FileIgnoreConfig))
+	copy(merged, existing.FileIgnoreConfig)
+
+	byName := make(map[string]int, len(merged))
+	for i, config := range merged {
+		byName[config.FileName] = i
+	}
+
+	for _, config := range suggested {
+		i, ok := byName[config.FileName]
+		if !ok {
+			byName[config.FileName] = len(merged)
+			merged = append(merged, config)
+			continue
+		}
+		if !merged[i].IsPattern() && merged[i].Checksum != config.Checksum {
+			merged[i].Checksum = config.Checksum
+		}
+	}
+	return merged
+}
+
+//LoadTalismanRCConfigs reads path's FileIgnoreConfig entries, e.g. for seeding NewPatternIgnores's
+//rootConfigs from the repo-root .talismanrc. A missing file is not an error: it contributes no entries.
+func LoadTalismanRCConfigs(path string) ([]FileIgnoreConfig, error) {
+	rc, _, err := readTalismanRC(path)
+	if err != nil {
+		return nil, err
+	}
+	return rc.FileIgnoreConfig, nil
+}
+
+//readTalismanRC reads and parses path, returning its entries plus any comment/blank lines at the
+//top of the file so they can be preserved verbatim when the file is rewritten. A missing file is
+//not an error: it is treated as an empty .talismanrc.
+func readTalismanRC(path string) (TalismanRCIgnore, string, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return TalismanRCIgnore{}, "", nil
+	}
+	if err != nil {
+		return TalismanRCIgnore{}, "", err
+	}
+
+	var rc TalismanRCIgnore
+	if err := yaml.Unmarshal(content, &rc); err != nil {
+		return TalismanRCIgnore{}, "", err
+	}
+	return rc, leadingComments(string(content)), nil
+}
+
+//leadingComments returns the contiguous run of blank and "#"-prefixed lines at the top of content,
+//which is as much of a user's hand-written commentary as a plain YAML round-trip can preserve.
+func leadingComments(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func writeTalismanRC(path string, leadingComments string, configs []FileIgnoreConfig) error {
+	rc := TalismanRCIgnore{FileIgnoreConfig: configs}
+	body, err := yaml.Marshal(&rc)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(leadingComments+string(body)), 0644)
+}
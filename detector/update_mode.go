@@ -0,0 +1,31 @@
+package detector
+
+//UpdateMode controls how WriteTalismanRC reconciles newly suggested FileIgnoreConfig entries with
+//an existing .talismanrc.
+type UpdateMode string
+
+const (
+	//UpdateModeMerge appends new entries and refreshes checksums for files that changed since they
+	//were last ignored, leaving the other existing entries as they are. The file is rewritten from
+	//its parsed fileignoreconfig entries: only the comment lines at its top are kept, and comments
+	//further down or other top-level keys are not.
+	UpdateModeMerge UpdateMode = "merge"
+	//UpdateModeReplace discards the existing file's entries in favour of the newly suggested ones.
+	UpdateModeReplace UpdateMode = "replace"
+	//UpdateModeInteractive behaves like UpdateModeMerge but asks for confirmation before adding
+	//each new entry, in the style of `git add -p`.
+	UpdateModeInteractive UpdateMode = "interactive"
+)
+
+//ParseUpdateMode parses a --update-rc flag value, defaulting to UpdateModeMerge since merging
+//without being asked is the safer behaviour for an unrecognised value.
+func ParseUpdateMode(name string) UpdateMode {
+	switch UpdateMode(name) {
+	case UpdateModeReplace:
+		return UpdateModeReplace
+	case UpdateModeInteractive:
+		return UpdateModeInteractive
+	default:
+		return UpdateModeMerge
+	}
+}
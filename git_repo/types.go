@@ -0,0 +1,4 @@
+package git_repo
+
+//FilePath represents the path of a file, relative to the repository root, that talisman is operating upon
+type FilePath string
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"os"
+	"talisman/cmd"
+)
+
+func main() {
+	repoRoot, err := os.Getwd()
+	if err != nil {
+		os.Exit(1)
+	}
+	os.Exit(cmd.Execute(os.Args[1:], repoRoot))
+}